@@ -0,0 +1,169 @@
+// Package backup snapshots exactly what a `tenderly push` sent to the
+// Tenderly API, so a later `tenderly push --from-backup` can replay the
+// same contracts without needing the original build artifacts. This
+// closes the reproducibility gap when build/ is gitignored: re-deploys,
+// promotions from staging to prod, and audits months later all just need
+// the backup directory.
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tenderly/tenderly-cli/model"
+	"github.com/tenderly/tenderly-cli/rest/payloads"
+	"github.com/tenderly/tenderly-cli/truffle"
+)
+
+const (
+	manifestFileName = "manifest.json"
+	configFileName   = "config.json"
+)
+
+// ContractEntry indexes a single backed-up contract by where it was
+// pushed, so --from-backup can tell which file on disk corresponds to
+// which (project, network, address) tuple.
+type ContractEntry struct {
+	ProjectSlug string `json:"project_slug"`
+	NetworkID   string `json:"network_id,omitempty"`
+	Address     string `json:"address,omitempty"`
+	ContractID  string `json:"contract_id,omitempty"`
+	File        string `json:"file"`
+}
+
+// Manifest is the backup directory's index, written alongside the
+// per-contract JSON files and the resolved compiler config.
+type Manifest struct {
+	Tag        string          `json:"tag,omitempty"`
+	CLIVersion string          `json:"cli_version"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Contracts  []ContractEntry `json:"contracts"`
+}
+
+// Write snapshots configPayload, every pushed contract and the server's
+// returned contract IDs into dir, indexed by manifest.json.
+func Write(dir string, projectSlug string, tag string, cliVersion string, configPayload *payloads.Config, contracts []truffle.Contract, pushed []*model.Contract) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	if configPayload != nil {
+		configJson, err := json.MarshalIndent(configPayload, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, configFileName), configJson, 0644); err != nil {
+			return err
+		}
+	}
+
+	manifest := Manifest{
+		Tag:        tag,
+		CLIVersion: cliVersion,
+		CreatedAt:  time.Now(),
+	}
+
+	for _, contract := range contracts {
+		contractJson, err := json.MarshalIndent(contract, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fileName := sanitizeFileName(contract.Name) + ".json"
+		if err := ioutil.WriteFile(filepath.Join(dir, fileName), contractJson, 0644); err != nil {
+			return err
+		}
+
+		if len(contract.Networks) == 0 {
+			manifest.Contracts = append(manifest.Contracts, ContractEntry{
+				ProjectSlug: projectSlug,
+				File:        fileName,
+			})
+			continue
+		}
+
+		for networkID, network := range contract.Networks {
+			entry := ContractEntry{
+				ProjectSlug: projectSlug,
+				NetworkID:   networkID,
+				Address:     network.Address,
+				File:        fileName,
+			}
+
+			for _, pushedContract := range pushed {
+				if strings.EqualFold(pushedContract.Address, network.Address) && strings.EqualFold(pushedContract.NetworkID, networkID) {
+					entry.ContractID = pushedContract.ID
+					break
+				}
+			}
+
+			manifest.Contracts = append(manifest.Contracts, entry)
+		}
+	}
+
+	manifestJson, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, manifestFileName), manifestJson, 0644)
+}
+
+// Read loads a backup directory written by Write, returning the contracts
+// and compiler config in a shape ready to feed back into the upload path.
+func Read(dir string) (*Manifest, []truffle.Contract, *payloads.Config, error) {
+	manifestJson, err := ioutil.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to read backup manifest: %s", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestJson, &manifest); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid backup manifest: %s", err)
+	}
+
+	var configPayload *payloads.Config
+	configPath := filepath.Join(dir, configFileName)
+	if _, err := os.Stat(configPath); err == nil {
+		configJson, err := ioutil.ReadFile(configPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := json.Unmarshal(configJson, &configPayload); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid backup config: %s", err)
+		}
+	}
+
+	seenFiles := make(map[string]bool)
+	var contracts []truffle.Contract
+	for _, entry := range manifest.Contracts {
+		if seenFiles[entry.File] {
+			continue
+		}
+		seenFiles[entry.File] = true
+
+		contractJson, err := ioutil.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to read backed up contract %s: %s", entry.File, err)
+		}
+
+		var contract truffle.Contract
+		if err := json.Unmarshal(contractJson, &contract); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid backed up contract %s: %s", entry.File, err)
+		}
+
+		contracts = append(contracts, contract)
+	}
+
+	return &manifest, contracts, configPayload, nil
+}
+
+func sanitizeFileName(name string) string {
+	replacer := strings.NewReplacer("/", "_", "\\", "_", " ", "_")
+	return replacer.Replace(name)
+}
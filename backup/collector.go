@@ -0,0 +1,36 @@
+package backup
+
+import (
+	"sync"
+
+	"github.com/tenderly/tenderly-cli/model"
+)
+
+// Collector accumulates the contracts returned by the API across many
+// concurrent uploads, so a backup can be written once the whole push is
+// done. Safe for concurrent use.
+type Collector struct {
+	mu     sync.Mutex
+	pushed []*model.Contract
+}
+
+// NewCollector creates an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add records the contracts returned by a single upload response.
+func (c *Collector) Add(contracts []*model.Contract) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pushed = append(c.pushed, contracts...)
+}
+
+// All returns every contract recorded so far.
+func (c *Collector) All() []*model.Contract {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pushed
+}
@@ -0,0 +1,181 @@
+package buildprovider
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tenderly/tenderly-cli/rest/payloads"
+	"github.com/tenderly/tenderly-cli/truffle"
+)
+
+func init() {
+	Register(&FoundryProvider{})
+}
+
+const (
+	foundryConfigFile   = "foundry.toml"
+	foundryOutDir       = "out"
+	foundryBroadcastDir = "broadcast"
+)
+
+// foundryArtifact mirrors the JSON forge writes per contract under
+// out/<File>.sol/<Name>.json.
+type foundryArtifact struct {
+	Abi      json.RawMessage `json:"abi"`
+	Bytecode struct {
+		Object string `json:"object"`
+	} `json:"bytecode"`
+}
+
+// foundryBroadcast mirrors a single run's receipt file under
+// broadcast/<Script>.s.sol/<chainID>/run-latest.json.
+type foundryBroadcast struct {
+	Transactions []struct {
+		ContractName    string `json:"contractName"`
+		ContractAddress string `json:"contractAddress"`
+	} `json:"transactions"`
+}
+
+// FoundryProvider reads contracts compiled by forge, combining the out/
+// directory (ABI + bytecode) with broadcast/ receipts (per-network
+// addresses) left behind by `forge script --broadcast`.
+type FoundryProvider struct {
+	root string
+}
+
+func (p *FoundryProvider) Name() string {
+	return "foundry"
+}
+
+func (p *FoundryProvider) Detect(root string) bool {
+	_, err := os.Stat(filepath.Join(root, foundryConfigFile))
+	return err == nil
+}
+
+func (p *FoundryProvider) LoadConfig() (*BuildConfig, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	p.root = root
+
+	return &BuildConfig{BuildDirectoryPath: filepath.Join(root, foundryOutDir)}, nil
+}
+
+func (p *FoundryProvider) LoadContracts(networks []string) ([]truffle.Contract, int, error) {
+	deploymentsByName, err := p.loadBroadcasts(networks)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var contracts []truffle.Contract
+	numberWithNetwork := 0
+
+	outRoot := filepath.Join(p.root, foundryOutDir)
+	err = filepath.Walk(outRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var artifact foundryArtifact
+		if err := json.Unmarshal(raw, &artifact); err != nil || len(artifact.Abi) == 0 {
+			return nil
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		contract := truffle.Contract{
+			Name:     name,
+			Abi:      artifact.Abi,
+			Bytecode: artifact.Bytecode.Object,
+		}
+
+		if networksForContract, ok := deploymentsByName[name]; ok {
+			contract.Networks = networksForContract
+			numberWithNetwork++
+		}
+
+		contracts = append(contracts, contract)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return contracts, numberWithNetwork, nil
+}
+
+// loadBroadcasts walks broadcast/*/<chainID>/run-latest.json, keeping only
+// chain IDs present in networks (or all of them when networks is empty),
+// and indexes the deployed addresses by contract name.
+func (p *FoundryProvider) loadBroadcasts(networks []string) (map[string]map[string]truffle.ContractNetwork, error) {
+	broadcastRoot := filepath.Join(p.root, foundryBroadcastDir)
+	if _, err := os.Stat(broadcastRoot); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, networkID := range networks {
+		wanted[networkID] = true
+	}
+
+	result := make(map[string]map[string]truffle.ContractNetwork)
+	err := filepath.Walk(broadcastRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Base(path) != "run-latest.json" {
+			return nil
+		}
+
+		chainID := filepath.Base(filepath.Dir(path))
+		if len(wanted) > 0 && !wanted[chainID] {
+			return nil
+		}
+
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var broadcast foundryBroadcast
+		if err := json.Unmarshal(raw, &broadcast); err != nil {
+			return nil
+		}
+
+		for _, tx := range broadcast.Transactions {
+			if tx.ContractName == "" || tx.ContractAddress == "" {
+				continue
+			}
+			if result[tx.ContractName] == nil {
+				result[tx.ContractName] = make(map[string]truffle.ContractNetwork)
+			}
+			result[tx.ContractName][chainID] = truffle.ContractNetwork{Address: tx.ContractAddress}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CompilerConfig doesn't send solc settings yet: foundry.toml can set solc
+// version and optimizer settings per profile, and forge's own artifacts
+// carry the resolved settings in each contract's "metadata" field, but
+// neither is parsed here. Say so explicitly rather than silently pushing
+// contracts with no compiler settings.
+func (p *FoundryProvider) CompilerConfig() (*payloads.Config, error) {
+	logrus.Warn("Compiler settings aren't detected for Foundry projects yet; pushed contracts won't include them, which may affect verification.")
+	return nil, nil
+}
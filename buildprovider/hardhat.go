@@ -0,0 +1,202 @@
+package buildprovider
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tenderly/tenderly-cli/rest/payloads"
+	"github.com/tenderly/tenderly-cli/truffle"
+)
+
+func init() {
+	Register(&HardhatProvider{})
+}
+
+const (
+	hardhatArtifactsDir  = "artifacts"
+	hardhatCacheDir      = "cache"
+	hardhatDeploymentDir = "deployments"
+	hardhatConfigFile    = "hardhat.config.js"
+	hardhatConfigFileTs  = "hardhat.config.ts"
+)
+
+// hardhatArtifact mirrors the JSON hardhat writes per contract under
+// artifacts/<path>/<Name>.json.
+type hardhatArtifact struct {
+	ContractName string          `json:"contractName"`
+	SourceName   string          `json:"sourceName"`
+	Abi          json.RawMessage `json:"abi"`
+	Bytecode     string          `json:"bytecode"`
+}
+
+// hardhatDeployment mirrors the JSON hardhat-deploy writes per network
+// under deployments/<network>/<Name>.json.
+type hardhatDeployment struct {
+	Address string `json:"address"`
+}
+
+// HardhatProvider reads contracts compiled by Hardhat, combining the
+// artifacts directory (ABI + bytecode) with hardhat-deploy's deployments
+// directory (per-network addresses) when present.
+type HardhatProvider struct {
+	root string
+}
+
+func (p *HardhatProvider) Name() string {
+	return "hardhat"
+}
+
+func (p *HardhatProvider) Detect(root string) bool {
+	for _, name := range []string{hardhatConfigFile, hardhatConfigFileTs} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *HardhatProvider) LoadConfig() (*BuildConfig, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	p.root = root
+
+	return &BuildConfig{BuildDirectoryPath: filepath.Join(root, hardhatArtifactsDir)}, nil
+}
+
+func (p *HardhatProvider) LoadContracts(networks []string) ([]truffle.Contract, int, error) {
+	deploymentsByName, err := p.loadDeployments(networks)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var contracts []truffle.Contract
+	numberWithNetwork := 0
+
+	artifactsRoot := filepath.Join(p.root, hardhatArtifactsDir)
+	err = filepath.Walk(artifactsRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".json") || strings.HasSuffix(path, ".dbg.json") {
+			return nil
+		}
+		if strings.Contains(path, string(os.PathSeparator)+hardhatCacheDir+string(os.PathSeparator)) {
+			return nil
+		}
+
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var artifact hardhatArtifact
+		if err := json.Unmarshal(raw, &artifact); err != nil || artifact.ContractName == "" {
+			return nil
+		}
+
+		contract := truffle.Contract{
+			Name:       artifact.ContractName,
+			Abi:        artifact.Abi,
+			Bytecode:   artifact.Bytecode,
+			SourcePath: artifact.SourceName,
+		}
+
+		if networksForContract, ok := deploymentsByName[artifact.ContractName]; ok {
+			contract.Networks = networksForContract
+			numberWithNetwork++
+		}
+
+		contracts = append(contracts, contract)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return contracts, numberWithNetwork, nil
+}
+
+// loadDeployments reads deployments/<network>/<Name>.json for every
+// requested network (or every network under deployments/ when none are
+// given) and indexes the resulting addresses by contract name.
+//
+// This only finds addresses for projects using the hardhat-deploy plugin,
+// which is what writes the deployments/ directory. A plain Hardhat project
+// that deploys through a custom script has no deployments/ directory, so
+// LoadContracts reports zero networked contracts for it and the push is
+// rejected upstream as "no migrated contracts detected" — the same outcome
+// Truffle has for an unmigrated build. Supporting that case needs a second
+// address source (e.g. parsing deploy scripts or an explicit address flag)
+// and isn't handled here yet.
+func (p *HardhatProvider) loadDeployments(networks []string) (map[string]map[string]truffle.ContractNetwork, error) {
+	deploymentsRoot := filepath.Join(p.root, hardhatDeploymentDir)
+	if _, err := os.Stat(deploymentsRoot); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	networkDirs := networks
+	if len(networkDirs) == 0 {
+		entries, err := ioutil.ReadDir(deploymentsRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				networkDirs = append(networkDirs, entry.Name())
+			}
+		}
+	}
+
+	result := make(map[string]map[string]truffle.ContractNetwork)
+	for _, networkID := range networkDirs {
+		networkDir := filepath.Join(deploymentsRoot, networkID)
+		entries, err := ioutil.ReadDir(networkDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+
+			raw, err := ioutil.ReadFile(filepath.Join(networkDir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			var deployment hardhatDeployment
+			if err := json.Unmarshal(raw, &deployment); err != nil || deployment.Address == "" {
+				continue
+			}
+
+			name := strings.TrimSuffix(entry.Name(), ".json")
+			if result[name] == nil {
+				result[name] = make(map[string]truffle.ContractNetwork)
+			}
+			result[name][networkID] = truffle.ContractNetwork{Address: deployment.Address}
+		}
+	}
+
+	return result, nil
+}
+
+// CompilerConfig doesn't send solc settings yet: unlike Truffle's
+// truffle-config.js, Hardhat's own config is a JS/TS module this CLI can't
+// safely evaluate, and the compiler settings Tenderly needs for
+// verification would have to come from artifacts/build-info/*.json
+// instead. Until that's parsed, say so explicitly rather than silently
+// pushing contracts with no compiler settings.
+func (p *HardhatProvider) CompilerConfig() (*payloads.Config, error) {
+	logrus.Warn("Compiler settings aren't detected for Hardhat projects yet; pushed contracts won't include them, which may affect verification.")
+	return nil, nil
+}
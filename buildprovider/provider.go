@@ -0,0 +1,74 @@
+// Package buildprovider abstracts away the build tool a project was
+// compiled with, so `tenderly push` can read contracts produced by
+// Truffle, Hardhat or Foundry through a single interface instead of
+// hard-coding Truffle's build directory layout.
+package buildprovider
+
+import (
+	"fmt"
+
+	"github.com/tenderly/tenderly-cli/rest/payloads"
+	"github.com/tenderly/tenderly-cli/truffle"
+)
+
+// BuildConfig holds the parts of a project's build configuration that are
+// common across frameworks and needed to locate and upload contracts.
+type BuildConfig struct {
+	BuildDirectoryPath string
+}
+
+// Provider is implemented by each supported build framework. Exactly one
+// Provider is selected per project, either through auto-detection or the
+// `--framework` override.
+type Provider interface {
+	// Name identifies the provider, used for the --framework flag and the
+	// project config's `framework:` key.
+	Name() string
+	// Detect reports whether root looks like a project built with this
+	// framework, based on files it leaves on disk.
+	Detect(root string) bool
+	// LoadConfig resolves the framework's build configuration, most
+	// importantly the directory compiled artifacts are read from.
+	LoadConfig() (*BuildConfig, error)
+	// LoadContracts reads every compiled contract from the build
+	// directory, filtered to the given networks, and reports how many of
+	// them have at least one deployed network.
+	LoadContracts(networks []string) ([]truffle.Contract, int, error)
+	// CompilerConfig builds the compiler settings payload sent alongside
+	// the contracts, in whatever shape this framework's config exposes it.
+	CompilerConfig() (*payloads.Config, error)
+}
+
+var providers []Provider
+
+// Register adds a Provider to the set considered by Detect. Called from
+// each framework implementation's init().
+func Register(provider Provider) {
+	providers = append(providers, provider)
+}
+
+// Detect returns the first registered Provider whose Detect(root) reports
+// true. Providers are checked in registration order, so more specific
+// frameworks (e.g. Foundry, which can coexist with a Hardhat config in the
+// same repo) should register before more general ones.
+func Detect(root string) (Provider, error) {
+	for _, provider := range providers {
+		if provider.Detect(root) {
+			return provider, nil
+		}
+	}
+
+	return nil, fmt.Errorf("couldn't detect a supported build framework (Truffle, Hardhat, Foundry) in: %s", root)
+}
+
+// Get returns the registered Provider with the given name, used for the
+// `--framework` override and the `framework:` project config key.
+func Get(name string) (Provider, error) {
+	for _, provider := range providers {
+		if provider.Name() == name {
+			return provider, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported framework: %s", name)
+}
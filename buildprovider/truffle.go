@@ -0,0 +1,57 @@
+package buildprovider
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tenderly/tenderly-cli/rest/payloads"
+	"github.com/tenderly/tenderly-cli/truffle"
+)
+
+func init() {
+	Register(&TruffleProvider{})
+}
+
+// TruffleProvider wraps the existing truffle package so Truffle projects
+// keep working unchanged now that build tooling is pluggable.
+type TruffleProvider struct {
+	config *truffle.Config
+}
+
+func (p *TruffleProvider) Name() string {
+	return "truffle"
+}
+
+func (p *TruffleProvider) Detect(root string) bool {
+	for _, name := range []string{"truffle-config.js", "truffle.js"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *TruffleProvider) LoadConfig() (*BuildConfig, error) {
+	config, err := truffle.GetTruffleConfig()
+	if err != nil {
+		return nil, err
+	}
+	p.config = config
+
+	return &BuildConfig{BuildDirectoryPath: config.AbsoluteBuildDirectoryPath()}, nil
+}
+
+func (p *TruffleProvider) LoadContracts(networks []string) ([]truffle.Contract, int, error) {
+	return truffle.GetTruffleContracts(p.config.AbsoluteBuildDirectoryPath(), networks)
+}
+
+func (p *TruffleProvider) CompilerConfig() (*payloads.Config, error) {
+	if p.config.ConfigType == truffle.NewTruffleConfigFile && p.config.Compilers != nil {
+		return payloads.ParseNewTruffleConfig(p.config.Compilers), nil
+	}
+	if p.config.ConfigType == truffle.OldTruffleConfigFile && p.config.Solc != nil {
+		return payloads.ParseOldTruffleConfig(p.config.Solc), nil
+	}
+
+	return nil, nil
+}
@@ -1,29 +1,46 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"github.com/pkg/errors"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 
-	"github.com/briandowns/spinner"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/tenderly/tenderly-cli/backup"
+	"github.com/tenderly/tenderly-cli/buildprovider"
 	"github.com/tenderly/tenderly-cli/config"
+	"github.com/tenderly/tenderly-cli/remotesource"
 	"github.com/tenderly/tenderly-cli/rest"
 	"github.com/tenderly/tenderly-cli/rest/payloads"
+	"github.com/tenderly/tenderly-cli/transfer"
 	"github.com/tenderly/tenderly-cli/truffle"
 	"github.com/tenderly/tenderly-cli/userError"
 )
 
 var deploymentTag string
 var pushNetworks string
+var pushConcurrency int
+var pushFramework string
+var pushBackupDir string
+var pushFromBackupDir string
+var pushSource string
+var pushChecksum string
 
 func init() {
 	pushCmd.PersistentFlags().StringVar(&deploymentTag, "tag", "", "Optional tag used for filtering and referencing pushed contracts")
 	pushCmd.PersistentFlags().StringVar(&pushNetworks, "networks", "", "A comma separated list of networks to push")
+	pushCmd.PersistentFlags().IntVar(&pushConcurrency, "concurrency", 4, "Number of contracts to upload in parallel")
+	pushCmd.PersistentFlags().StringVar(&pushFramework, "framework", "", "Build framework the project was compiled with: truffle, hardhat or foundry. Auto-detected when omitted")
+	pushCmd.PersistentFlags().StringVar(&pushBackupDir, "backup", "", "Directory to write a self-contained backup of everything that was pushed")
+	pushCmd.PersistentFlags().StringVar(&pushFromBackupDir, "from-backup", "", "Replay a previous --backup directory instead of reading local build artifacts")
+	pushCmd.PersistentFlags().StringVar(&pushSource, "source", "", "Fetch contracts from a tagged release instead of the local build dir, e.g. github://owner/repo@v1.2.3[:path]")
+	pushCmd.PersistentFlags().StringVar(&pushChecksum, "checksum", "", "Expected checksum of the --source archive, in the form sha256:<hex>")
 	rootCmd.AddCommand(pushCmd)
 }
 
@@ -31,19 +48,16 @@ var pushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Pushes the contracts to the configured project. After the contracts are pushed they are actively monitored by Tenderly.",
 	Run: func(cmd *cobra.Command, args []string) {
-		rest := newRest()
-
-		CheckLogin()
-
-		if !config.IsProjectInit() {
-			logrus.Error("You need to initiate the project first.\n\n",
-				"You can do this by using the ", colorizer.Bold(colorizer.Green("tenderly init")), " command.")
-			os.Exit(1)
-		}
-
-		logrus.Info("Setting up your project...")
-
-		err := uploadContracts(rest)
+		err := RunPush(PushOptions{
+			Tag:           deploymentTag,
+			Networks:      pushNetworks,
+			Concurrency:   pushConcurrency,
+			Framework:     pushFramework,
+			BackupDir:     pushBackupDir,
+			FromBackupDir: pushFromBackupDir,
+			Source:        pushSource,
+			Checksum:      pushChecksum,
+		})
 
 		if err != nil {
 			userError.LogErrorf("unable to upload contracts: %s", err)
@@ -54,15 +68,55 @@ var pushCmd = &cobra.Command{
 	},
 }
 
-func uploadContracts(rest *rest.Rest) error {
-	logrus.Info("Analyzing Truffle configuration...")
+// PushOptions controls a single `tenderly push` run. It mirrors the push
+// command's flags so the same logic can be driven from a `.tenderly.yml`
+// pipeline step instead of the CLI flags directly.
+type PushOptions struct {
+	Tag         string
+	Networks    string
+	Concurrency int
+	Framework   string
+	// Projects restricts the push to the given project slugs. Empty means
+	// every project configured in the project config.
+	Projects []string
+	// BackupDir, when set, writes a self-contained snapshot of everything
+	// pushed to this directory once the push succeeds.
+	BackupDir string
+	// FromBackupDir, when set, replays a directory written by a previous
+	// BackupDir push instead of reading local build artifacts.
+	FromBackupDir string
+	// Source, when set, fetches contracts from a tagged release archive
+	// instead of the local build dir, e.g. github://owner/repo@v1.2.3[:path].
+	Source string
+	// Checksum is the expected checksum of the Source archive, in the
+	// form sha256:<hex>.
+	Checksum string
+}
 
-	truffleConfig, err := MustGetTruffleConfig()
-	if err != nil {
-		return err
+// RunPush runs the full push flow: verifying the user is logged in and the
+// project is initialized, then uploading contracts per opts. It's exported
+// so other entry points, such as a `.tenderly.yml` pipeline step, can reuse
+// it without re-shelling out to the `push` command.
+func RunPush(opts PushOptions) error {
+	rest := newRest()
+
+	CheckLogin()
+
+	if !config.IsProjectInit() {
+		logrus.Error("You need to initiate the project first.\n\n",
+			"You can do this by using the ", colorizer.Bold(colorizer.Green("tenderly init")), " command.")
+		os.Exit(1)
 	}
 
-	networkIDs := extractNetworkIDs(pushNetworks)
+	logrus.Info("Setting up your project...")
+
+	return uploadContracts(rest, opts)
+}
+
+func uploadContracts(rest *rest.Rest, opts PushOptions) error {
+	logrus.Info("Analyzing build configuration...")
+
+	networkIDs := extractNetworkIDs(opts.Networks)
 
 	projectConfigurations, err := getProjectConfiguration()
 	if err != nil {
@@ -74,36 +128,103 @@ func uploadContracts(rest *rest.Rest) error {
 		)
 	}
 
+	// A single manager (and its dedup tracking) is shared across every
+	// project in this run, so the same network+address deployment is only
+	// uploaded once even when it's configured under more than one project.
+	progress := newPushProgress()
+	manager := transfer.NewManager(transfer.Config{
+		Concurrency: opts.Concurrency,
+		Watcher:     progress,
+		IsRetryable: isRetryableUploadError,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		<-interrupt
+		logrus.Warn("Stopping the upload, waiting for in-flight contracts to finish...")
+		cancel()
+	}()
+	defer progress.Stop()
+
 	for projectSlug, projectConfiguration := range projectConfigurations {
+		if len(opts.Projects) > 0 && !contains(opts.Projects, projectSlug) {
+			continue
+		}
+
 		logrus.Info(colorizer.Sprintf(
 			"Pushing Smart Contracts for project: %s",
 			colorizer.Bold(colorizer.Green(projectSlug)),
 		))
 
-		providedNetworksIDs := append(networkIDs, projectConfiguration.Networks...)
-		contracts, numberOfContractsWithANetwork, err := truffle.GetTruffleContracts(truffleConfig.AbsoluteBuildDirectoryPath(), providedNetworksIDs)
-		if err != nil {
-			return userError.NewUserError(
-				errors.Wrap(err, "unable to get truffle contracts"),
-				fmt.Sprintf("Couldn't read Truffle build files at: %s", truffleConfig.AbsoluteBuildDirectoryPath()),
-			)
+		var contracts []truffle.Contract
+		var numberOfContractsWithANetwork int
+		var configPayload *payloads.Config
+		var buildDirectoryPath string
+
+		if opts.FromBackupDir != "" {
+			contracts, configPayload, err = loadContractsFromBackup(opts.FromBackupDir)
+			if err != nil {
+				return err
+			}
+			buildDirectoryPath = opts.FromBackupDir
+			for _, contract := range contracts {
+				if len(contract.Networks) > 0 {
+					numberOfContractsWithANetwork++
+				}
+			}
+		} else if opts.Source != "" {
+			contracts, numberOfContractsWithANetwork, err = loadContractsFromSource(opts.Source, opts.Checksum)
+			if err != nil {
+				return err
+			}
+			buildDirectoryPath = opts.Source
+		} else {
+			var provider buildprovider.Provider
+			var buildConfig *buildprovider.BuildConfig
+			provider, buildConfig, err = resolveBuildProvider(opts.Framework, projectConfiguration.Framework)
+			if err != nil {
+				return err
+			}
+
+			logrus.Infof("Detected %s project, reading build artifacts from: %s", provider.Name(), buildConfig.BuildDirectoryPath)
+			buildDirectoryPath = buildConfig.BuildDirectoryPath
+
+			providedNetworksIDs := append(networkIDs, projectConfiguration.Networks...)
+			contracts, numberOfContractsWithANetwork, err = provider.LoadContracts(providedNetworksIDs)
+			if err != nil {
+				return userError.NewUserError(
+					errors.Wrap(err, "unable to get contracts"),
+					fmt.Sprintf("Couldn't read %s build files at: %s", provider.Name(), buildConfig.BuildDirectoryPath),
+				)
+			}
+
+			configPayload, err = provider.CompilerConfig()
+			if err != nil {
+				return userError.NewUserError(
+					errors.Wrap(err, "unable to get compiler config"),
+					fmt.Sprintf("Couldn't determine compiler settings for the %s project", provider.Name()),
+				)
+			}
 		}
 
 		if len(contracts) == 0 {
 			return userError.NewUserError(
-				fmt.Errorf("no contracts found in build dir: %s", truffleConfig.AbsoluteBuildDirectoryPath()),
+				fmt.Errorf("no contracts found in build dir: %s", buildDirectoryPath),
 				colorizer.Sprintf("No contracts detected in build directory: %s. "+
-					"This can happen when no contracts have been migrated yet or the %s hasn't been run yet.",
-					colorizer.Bold(colorizer.Red(truffleConfig.AbsoluteBuildDirectoryPath())),
-					colorizer.Bold(colorizer.Green("truffle compile")),
+					"This can happen when no contracts have been compiled yet.",
+					colorizer.Bold(colorizer.Red(buildDirectoryPath)),
 				),
 			)
 		}
 		if numberOfContractsWithANetwork == 0 {
 			return userError.NewUserError(
-				fmt.Errorf("no contracts with a netowrk found in build dir: %s", truffleConfig.AbsoluteBuildDirectoryPath()),
+				fmt.Errorf("no contracts with a netowrk found in build dir: %s", buildDirectoryPath),
 				colorizer.Sprintf("No migrated contracts detected in build directory: %s. This can happen when no contracts have been migrated yet.",
-					colorizer.Bold(colorizer.Red(truffleConfig.AbsoluteBuildDirectoryPath())),
+					colorizer.Bold(colorizer.Red(buildDirectoryPath)),
 				),
 			)
 		}
@@ -117,72 +238,46 @@ func uploadContracts(rest *rest.Rest) error {
 			}
 		}
 
-		s := spinner.New(spinner.CharSets[33], 100*time.Millisecond)
-
-		s.Start()
+		backupDir := opts.BackupDir
+		if backupDir == "" {
+			backupDir = projectConfiguration.BackupDir
+		}
 
-		var configPayload *payloads.Config
-		if truffleConfig.ConfigType == truffle.NewTruffleConfigFile && truffleConfig.Compilers != nil {
-			configPayload = payloads.ParseNewTruffleConfig(truffleConfig.Compilers)
-		} else if truffleConfig.ConfigType == truffle.OldTruffleConfigFile && truffleConfig.Solc != nil {
-			configPayload = payloads.ParseOldTruffleConfig(truffleConfig.Solc)
+		var collector *backup.Collector
+		if backupDir != "" {
+			collector = backup.NewCollector()
 		}
 
-		response, err := rest.Contract.UploadContracts(payloads.UploadContractsRequest{
-			Contracts: contracts,
-			Config:    configPayload,
-			Tag:       deploymentTag,
-		}, projectSlug)
+		descriptors := buildUploadDescriptors(rest, contracts, configPayload, opts.Tag, projectSlug, collector)
 
-		s.Stop()
+		results := manager.Run(ctx, descriptors)
 
-		if err != nil {
-			return userError.NewUserError(
-				fmt.Errorf("failed uploading contracts: %s", err),
-				"Couldn't push contracts to the Tenderly servers",
-			)
+		var failures []string
+		for _, result := range results {
+			if result.Err != nil {
+				failures = append(failures, colorizer.Sprintf("• %s: %s", colorizer.Bold(colorizer.Red(result.Key)), result.Err))
+			}
 		}
 
-		if response.Error != nil {
+		if len(failures) > 0 {
 			return userError.NewUserError(
-				fmt.Errorf("api error uploading contracts: %s", response.Error.Slug),
-				response.Error.Message,
+				fmt.Errorf("failed uploading %d contract(s)", len(failures)),
+				fmt.Sprintf("Some of the contracts haven't been pushed. Below is the list of (contract, network, address) "+
+					"tuples that failed, along with the reason:\n%s",
+					strings.Join(failures, "\n"),
+				),
 			)
 		}
 
-		if len(response.Contracts) != numberOfContractsWithANetwork {
-			var nonPushedContracts []string
-
-			for _, contract := range contracts {
-				if len(contract.Networks) == 0 {
-					continue
-				}
-				for networkId, network := range contract.Networks {
-					var found bool
-					for _, pushedContract := range response.Contracts {
-						if pushedContract.Address == strings.ToLower(network.Address) && pushedContract.NetworkID == strings.ToLower(networkId) {
-							found = true
-							break
-						}
-					}
-					if !found {
-						nonPushedContracts = append(nonPushedContracts, colorizer.Sprintf(
-							"• %s on network %s with address %s",
-							colorizer.Bold(colorizer.Red(contract.Name)),
-							colorizer.Bold(colorizer.Red(networkId)),
-							colorizer.Bold(colorizer.Red(network.Address)),
-						))
-					}
-				}
+		if backupDir != "" {
+			err = backup.Write(backupDir, projectSlug, opts.Tag, config.GetString("version"), configPayload, contracts, collector.All())
+			if err != nil {
+				return userError.NewUserError(
+					errors.Wrap(err, "unable to write backup"),
+					fmt.Sprintf("Couldn't write backup to: %s", backupDir),
+				)
 			}
-
-			return userError.NewUserError(
-				fmt.Errorf("unexpected number of pushed contracts. Got: %d expected: %d", len(response.Contracts), len(contracts)),
-				fmt.Sprintf("Some of the contracts haven't been pushed. This can happen when the contract isn't deployed to a supported network or some other error might have occurred. "+
-					"Below is the list with all the contracts that weren't pushed successfully:\n%s",
-					strings.Join(nonPushedContracts, "\n"),
-				),
-			)
+			logrus.Infof("Wrote a backup of the pushed contracts to: %s", backupDir)
 		}
 
 		username := config.GetString(config.Username)
@@ -202,8 +297,248 @@ func uploadContracts(rest *rest.Rest) error {
 	return nil
 }
 
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadContractsFromBackup replays a directory written by a previous
+// `tenderly push --backup`, bypassing build tool detection entirely.
+func loadContractsFromBackup(dir string) ([]truffle.Contract, *payloads.Config, error) {
+	_, contracts, configPayload, err := backup.Read(dir)
+	if err != nil {
+		return nil, nil, userError.NewUserError(
+			errors.Wrap(err, "unable to read backup"),
+			fmt.Sprintf("Couldn't read backup directory: %s", dir),
+		)
+	}
+
+	return contracts, configPayload, nil
+}
+
+// loadContractsFromSource fetches and caches the release archive named by
+// a --source value, verifies it against checksum when given, and decodes
+// its build artifacts directly from the archive reader.
+func loadContractsFromSource(sourceURI string, checksum string) ([]truffle.Contract, int, error) {
+	source, err := remotesource.Parse(sourceURI)
+	if err != nil {
+		return nil, 0, userError.NewUserError(
+			err,
+			fmt.Sprintf("Couldn't parse --source value: %s", sourceURI),
+		)
+	}
+
+	cacheDir, err := remotesource.DefaultCacheDir()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	archive, err := remotesource.Fetch(source, cacheDir, checksum)
+	if err != nil {
+		return nil, 0, userError.NewUserError(
+			err,
+			fmt.Sprintf("Couldn't fetch --source archive: %s", sourceURI),
+		)
+	}
+
+	contracts, numberWithNetwork, err := remotesource.LoadContracts(archive, source.SubPath)
+	if err != nil {
+		return nil, 0, userError.NewUserError(
+			err,
+			fmt.Sprintf("Couldn't read build artifacts from --source archive: %s", sourceURI),
+		)
+	}
+
+	return contracts, numberWithNetwork, nil
+}
+
+// resolveBuildProvider picks the buildprovider.Provider to read contracts
+// from. The `--framework` flag (or PushOptions.Framework) takes precedence,
+// then the project's `framework:` config key, falling back to
+// auto-detection against the files present in the current directory.
+func resolveBuildProvider(optsFramework string, projectFramework string) (buildprovider.Provider, *buildprovider.BuildConfig, error) {
+	name := optsFramework
+	if name == "" {
+		name = projectFramework
+	}
+
+	var provider buildprovider.Provider
+	var err error
+	if name != "" {
+		provider, err = buildprovider.Get(name)
+	} else {
+		var root string
+		root, err = os.Getwd()
+		if err == nil {
+			provider, err = buildprovider.Detect(root)
+		}
+	}
+	if err != nil {
+		return nil, nil, userError.NewUserError(
+			err,
+			colorizer.Sprintf("Couldn't determine the build framework for this project. Pass %s to select one explicitly.",
+				colorizer.Bold(colorizer.Green("--framework {truffle,hardhat,foundry}")),
+			),
+		)
+	}
+
+	buildConfig, err := provider.LoadConfig()
+	if err != nil {
+		return nil, nil, userError.NewUserError(
+			errors.Wrap(err, "unable to load build config"),
+			fmt.Sprintf("Couldn't read %s build configuration", provider.Name()),
+		)
+	}
+
+	return provider, buildConfig, nil
+}
+
+// contractUploadDescriptor uploads a single contract, filtered down to a
+// single network when the contract is deployed to one, so it can be
+// scheduled independently by the transfer.TransferManager.
+type contractUploadDescriptor struct {
+	rest        *rest.Rest
+	contract    truffle.Contract
+	config      *payloads.Config
+	tag         string
+	projectSlug string
+	key         string
+	collector   *backup.Collector
+}
+
+func (d *contractUploadDescriptor) Key() string {
+	return d.key
+}
+
+func (d *contractUploadDescriptor) Do(ctx context.Context) error {
+	response, err := d.rest.Contract.UploadContract(d.contract, d.config, d.tag, d.projectSlug)
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return &permanentUploadError{fmt.Errorf("%s: %s", response.Error.Slug, response.Error.Message)}
+	}
+	if len(response.Contracts) == 0 && len(d.contract.Networks) > 0 {
+		return &permanentUploadError{fmt.Errorf("contract wasn't pushed, it might not be deployed to a supported network")}
+	}
+	if d.collector != nil {
+		d.collector.Add(response.Contracts)
+	}
+	return nil
+}
+
+// permanentUploadError marks an upload failure the API itself rejected
+// (a validation error, or a contract that isn't deployed to a supported
+// network) as opposed to a transient network or server error. Only
+// non-permanent errors are retried by the transfer.TransferManager.
+type permanentUploadError struct {
+	err error
+}
+
+func (e *permanentUploadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentUploadError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableUploadError reports whether a failed upload should be retried.
+// Network errors and unexpected failures are assumed transient; a
+// permanentUploadError means the API already rejected the request, so
+// retrying it would just fail again.
+func isRetryableUploadError(err error) bool {
+	_, permanent := err.(*permanentUploadError)
+	return !permanent
+}
+
+// buildUploadDescriptors splits contracts into one descriptor per
+// (contract, network) pair, deduplicated across projects by network+address
+// so the same deployment isn't uploaded twice in a single run: if two
+// projects share a deployment, only the first project to reach it pushes,
+// and the second gets back a transfer.Result with Skipped set rather than
+// a false Done. Contracts that aren't deployed anywhere (library contracts)
+// get one descriptor each. collector may be nil when no backup is being
+// written for this push.
+func buildUploadDescriptors(rest *rest.Rest, contracts []truffle.Contract, config *payloads.Config, tag string, projectSlug string, collector *backup.Collector) []transfer.Descriptor {
+	var descriptors []transfer.Descriptor
+
+	for _, contract := range contracts {
+		if len(contract.Networks) == 0 {
+			descriptors = append(descriptors, &contractUploadDescriptor{
+				rest:        rest,
+				contract:    contract,
+				config:      config,
+				tag:         tag,
+				projectSlug: projectSlug,
+				key:         fmt.Sprintf("%s (library contract)", contract.Name),
+				collector:   collector,
+			})
+			continue
+		}
+
+		for networkID, network := range contract.Networks {
+			single := contract
+			single.Networks = map[string]truffle.ContractNetwork{networkID: network}
+
+			descriptors = append(descriptors, &contractUploadDescriptor{
+				rest:        rest,
+				contract:    single,
+				config:      config,
+				tag:         tag,
+				projectSlug: projectSlug,
+				key:         fmt.Sprintf("%s on network %s with address %s", contract.Name, networkID, network.Address),
+				collector:   collector,
+			})
+		}
+	}
+
+	return descriptors
+}
+
+// pushProgress renders transfer.Updates as a multiline progress view,
+// replacing the single spinner that used to cover the whole upload.
+type pushProgress struct {
+	mu      sync.Mutex
+	started map[string]bool
+}
+
+func newPushProgress() *pushProgress {
+	return &pushProgress{started: make(map[string]bool)}
+}
+
+func (p *pushProgress) OnUpdate(update transfer.Update) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch update.Status {
+	case transfer.StatusStarted:
+		if !p.started[update.Key] {
+			p.started[update.Key] = true
+			logrus.Infof("Uploading: %s", update.Key)
+		}
+	case transfer.StatusRetrying:
+		logrus.Warnf("Retrying (attempt %d): %s: %s", update.Attempt, update.Key, update.Err)
+	case transfer.StatusDone:
+		logrus.Infof("Done: %s", update.Key)
+	case transfer.StatusSkipped:
+		logrus.Infof("Skipped (already pushed under another project in this run): %s", update.Key)
+	case transfer.StatusFailed:
+		logrus.Errorf("Failed: %s: %s", update.Key, update.Err)
+	}
+}
+
+func (p *pushProgress) Stop() {}
+
 type ProjectConfiguration struct {
-	Networks []string
+	Networks  []string
+	Framework string
+	BackupDir string
 }
 
 type ProjectConfigurationMap map[string]*ProjectConfiguration
@@ -240,6 +575,14 @@ func getProjectConfiguration() (ProjectConfigurationMap, error) {
 			}
 		}
 
+		if framework, ok := singleConfigMap["framework"].(string); ok {
+			projectConfig.Framework = framework
+		}
+
+		if backupDir, ok := singleConfigMap["backup"].(string); ok {
+			projectConfig.BackupDir = backupDir
+		}
+
 		projectConfigurationMap[projectSlug] = projectConfig
 	}
 
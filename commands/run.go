@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/tenderly/tenderly-cli/pipeline"
+	"github.com/tenderly/tenderly-cli/userError"
+	"gopkg.in/yaml.v2"
+)
+
+var pipelineFile string
+
+func init() {
+	runCmd.PersistentFlags().StringVar(&pipelineFile, "file", ".tenderly.yml", "Path to the pipeline file to run")
+	rootCmd.AddCommand(runCmd)
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Runs the push/verify/tag/advisor/monitor steps declared in a .tenderly.yml pipeline file.",
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := pipeline.Load(pipelineFile)
+		if err != nil {
+			userError.LogErrorf("unable to parse pipeline file: %s", err)
+			os.Exit(1)
+		}
+
+		env := pipeline.EnvFromCI(os.Getenv)
+
+		if err := pipeline.Run(file, env); err != nil {
+			userError.LogErrorf("pipeline failed: %s", err)
+			os.Exit(1)
+		}
+
+		logrus.Info("Pipeline finished successfully.")
+	},
+}
+
+// pushStepConfig is the shape of a `push:` block in .tenderly.yml.
+type pushStepConfig struct {
+	When        *pipeline.When `yaml:"when,omitempty"`
+	Projects    []string       `yaml:"projects,omitempty"`
+	Tag         string         `yaml:"tag,omitempty"`
+	Networks    string         `yaml:"networks,omitempty"`
+	Concurrency int            `yaml:"concurrency,omitempty"`
+	Framework   string         `yaml:"framework,omitempty"`
+}
+
+type pushStep struct {
+	config pushStepConfig
+}
+
+func (s *pushStep) Name() string {
+	return "push"
+}
+
+func (s *pushStep) When() *pipeline.When {
+	return s.config.When
+}
+
+func (s *pushStep) Run(env pipeline.Env) error {
+	return RunPush(PushOptions{
+		Tag:         s.config.Tag,
+		Networks:    s.config.Networks,
+		Concurrency: s.config.Concurrency,
+		Framework:   s.config.Framework,
+		Projects:    s.config.Projects,
+	})
+}
+
+func init() {
+	pipeline.Register("push", func(raw yaml.MapSlice) (pipeline.Step, error) {
+		config := pushStepConfig{Concurrency: 4}
+		if err := pipeline.Decode(raw, &config); err != nil {
+			return nil, err
+		}
+
+		return &pushStep{config: config}, nil
+	})
+
+	for _, kind := range []string{"verify", "tag", "monitor", "advisor"} {
+		registerStubStep(kind)
+	}
+}
+
+// stubStepConfig is the shape accepted by the verify/tag/monitor/advisor
+// steps, which aren't implemented yet. It only decodes the when guard; any
+// other keys in the block are accepted and ignored so a .tenderly.yml
+// written against the advertised schema still parses.
+type stubStepConfig struct {
+	When *pipeline.When `yaml:"when,omitempty"`
+}
+
+// stubStep is a placeholder for a pipeline step kind that's declared and
+// documented but not implemented yet. Unlike a real step, Run always fails:
+// a .tenderly.yml that reaches one of these steps is relying on behavior
+// that doesn't exist yet, and reporting success would tell the rest of a CI
+// pipeline (e.g. a deploy gate waiting on verify/monitor) that something
+// happened when nothing did. Users who don't want that can guard the step
+// out with `when:` until it's implemented for real.
+type stubStep struct {
+	name   string
+	config stubStepConfig
+}
+
+func (s *stubStep) Name() string {
+	return s.name
+}
+
+func (s *stubStep) When() *pipeline.When {
+	return s.config.When
+}
+
+func (s *stubStep) Run(env pipeline.Env) error {
+	return fmt.Errorf("%s step isn't implemented yet", s.name)
+}
+
+func registerStubStep(kind string) {
+	pipeline.Register(kind, func(raw yaml.MapSlice) (pipeline.Step, error) {
+		config := stubStepConfig{}
+		if err := pipeline.Decode(raw, &config); err != nil {
+			return nil, err
+		}
+
+		return &stubStep{name: kind, config: config}, nil
+	})
+}
@@ -0,0 +1,71 @@
+package pipeline
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// File is a parsed .tenderly.yml: an ordered list of steps, in the order
+// they appeared in the file.
+type File struct {
+	Steps []Step
+}
+
+// Load reads and parses a .tenderly.yml file at path. Step order is
+// preserved using yaml.MapSlice, since a plain map[string]interface{}
+// would discard it.
+func Load(path string) (*File, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(raw, &document); err != nil {
+		return nil, fmt.Errorf("invalid pipeline file %s: %s", path, err)
+	}
+
+	file := &File{}
+	for _, item := range document {
+		kind, ok := item.Key.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid pipeline file %s: step key %v is not a string", path, item.Key)
+		}
+
+		body, err := asMapSlice(item.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline file %s: step %q: %s", path, kind, err)
+		}
+
+		step, err := build(kind, body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline file %s: step %q: %s", path, kind, err)
+		}
+
+		file.Steps = append(file.Steps, step)
+	}
+
+	return file, nil
+}
+
+func asMapSlice(value interface{}) (yaml.MapSlice, error) {
+	body, ok := value.(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("expected a mapping")
+	}
+
+	return body, nil
+}
+
+// Decode re-marshals a step's raw yaml body and unmarshals it into out,
+// which should be a pointer to the step's config struct.
+func Decode(raw yaml.MapSlice, out interface{}) error {
+	bytes, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(bytes, out)
+}
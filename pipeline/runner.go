@@ -0,0 +1,40 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Run executes every step in the file in order, skipping those whose
+// When() guard doesn't match env, and failing fast on the first step
+// error.
+func Run(file *File, env Env) error {
+	for _, step := range file.Steps {
+		if !step.When().Match(env) {
+			logrus.Debugf("Skipping step %q: when: guard didn't match", step.Name())
+			continue
+		}
+
+		logrus.Infof("Running step: %s", step.Name())
+
+		if err := step.Run(env); err != nil {
+			return fmt.Errorf("step %q failed: %s", step.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// EnvFromCI builds an Env from the CI environment variables most CI
+// providers can be configured to export: CI_BRANCH, CI_EVENT and
+// CI_NETWORK. CI_NETWORK isn't a standard variable any CI provider sets on
+// its own, so it only has a value when the pipeline itself exports it
+// (e.g. a job matrix that sets CI_NETWORK to the network it's deploying).
+func EnvFromCI(getenv func(string) string) Env {
+	return Env{
+		Branch:  getenv("CI_BRANCH"),
+		Event:   getenv("CI_EVENT"),
+		Network: getenv("CI_NETWORK"),
+	}
+}
@@ -0,0 +1,45 @@
+// Package pipeline runs a `.tenderly.yml` file as an ordered sequence of
+// steps, modeled on drone/woodpecker's step yaml: each top-level key
+// (push, verify, advisor, monitor, ...) is one step, guarded by an
+// optional `when:` block evaluated against the current CI environment.
+package pipeline
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Step is a single named block in a .tenderly.yml file.
+type Step interface {
+	// Name identifies the step for logging, e.g. "push".
+	Name() string
+	// When returns the step's guard, or nil if it always runs.
+	When() *When
+	// Run executes the step. It's only called once When().Match(env)
+	// has already been checked by Run.
+	Run(env Env) error
+}
+
+// Factory builds a Step from the raw yaml node under its key in the
+// pipeline file.
+type Factory func(raw yaml.MapSlice) (Step, error)
+
+var factories = make(map[string]Factory)
+
+// Register associates a step kind (the yaml key, e.g. "push") with the
+// Factory that builds it. Called from init() in the package that owns the
+// step's actual behavior, so pipeline itself stays free of dependencies on
+// commands/rest/etc.
+func Register(kind string, factory Factory) {
+	factories[kind] = factory
+}
+
+func build(kind string, raw yaml.MapSlice) (Step, error) {
+	factory, ok := factories[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown pipeline step: %s", kind)
+	}
+
+	return factory(raw)
+}
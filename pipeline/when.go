@@ -0,0 +1,44 @@
+package pipeline
+
+// Env carries the ambient CI information a When guard is evaluated
+// against. It's populated from environment variables such as CI_BRANCH
+// and CI_EVENT so the same .tenderly.yml works across CI providers that
+// expose them under those names.
+type Env struct {
+	Branch  string
+	Event   string
+	Network string
+}
+
+// When is a step's guard: every non-empty field must contain the
+// corresponding Env value for the step to run. An empty field imposes no
+// constraint.
+type When struct {
+	Branch  []string `yaml:"branch,omitempty"`
+	Event   []string `yaml:"event,omitempty"`
+	Network []string `yaml:"network,omitempty"`
+}
+
+// Match reports whether env satisfies every constraint on w. A nil When
+// always matches.
+func (w *When) Match(env Env) bool {
+	if w == nil {
+		return true
+	}
+
+	return matches(w.Branch, env.Branch) && matches(w.Event, env.Event) && matches(w.Network, env.Network)
+}
+
+func matches(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, candidate := range allowed {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package remotesource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/tenderly/tenderly-cli/truffle"
+)
+
+// artifact mirrors the build artifact JSON shape both Truffle
+// (build/contracts/<Name>.json) and combined Hardhat artifacts use:
+// contract name, ABI, bytecode and an optional per-network deployment map.
+type artifact struct {
+	ContractName string                                 `json:"contractName"`
+	Abi          json.RawMessage                         `json:"abi"`
+	Bytecode     string                                  `json:"bytecode"`
+	Networks     map[string]truffle.ContractNetwork `json:"networks"`
+}
+
+// LoadContracts decodes every build artifact JSON file in archive
+// (a .tar.gz, as served by GitHub's codeload/release endpoints) directly
+// from the in-memory reader, without extracting it to disk. subPath, when
+// non-empty, restricts discovery to that directory inside the archive
+// (GitHub tarballs nest everything under a single `<repo>-<ref>/` prefix,
+// which is stripped automatically).
+func LoadContracts(archive []byte, subPath string) ([]truffle.Contract, int, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, 0, fmt.Errorf("not a gzip-compressed archive: %s", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	var contracts []truffle.Contract
+	numberWithNetwork := 0
+
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, ".json") {
+			continue
+		}
+
+		name := stripArchiveRootPrefix(header.Name)
+		if subPath != "" && !strings.HasPrefix(name, strings.TrimSuffix(subPath, "/")+"/") {
+			continue
+		}
+
+		raw, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to read %s from archive: %s", header.Name, err)
+		}
+
+		var parsed artifact
+		if err := json.Unmarshal(raw, &parsed); err != nil || parsed.ContractName == "" || len(parsed.Abi) == 0 {
+			continue
+		}
+
+		contract := truffle.Contract{
+			Name:     parsed.ContractName,
+			Abi:      parsed.Abi,
+			Bytecode: parsed.Bytecode,
+			Networks: parsed.Networks,
+		}
+		if len(contract.Networks) > 0 {
+			numberWithNetwork++
+		}
+
+		contracts = append(contracts, contract)
+	}
+
+	return contracts, numberWithNetwork, nil
+}
+
+// stripArchiveRootPrefix removes the single top-level directory GitHub's
+// codeload archives wrap every entry in (e.g. "my-repo-1.2.3/").
+func stripArchiveRootPrefix(name string) string {
+	parts := strings.SplitN(path.Clean(name), "/", 2)
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return name
+}
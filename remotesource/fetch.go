@@ -0,0 +1,86 @@
+package remotesource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCacheDir returns ~/.tenderly/cache, where fetched archives are
+// kept so repeated pushes of the same tag are offline-friendly.
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".tenderly", "cache"), nil
+}
+
+// Fetch downloads source's archive, or returns it from cacheDir if it was
+// already fetched. When checksum is non-empty (in the form
+// "sha256:<hex>"), the archive's contents are verified against it before
+// being returned or cached.
+func Fetch(source *Source, cacheDir string, checksum string) ([]byte, error) {
+	cacheKey := sha256.Sum256([]byte(source.ArchiveURL))
+	cachePath := filepath.Join(cacheDir, hex.EncodeToString(cacheKey[:]))
+
+	if archive, err := ioutil.ReadFile(cachePath); err == nil {
+		if err := verifyChecksum(archive, checksum); err != nil {
+			return nil, err
+		}
+		return archive, nil
+	}
+
+	response, err := http.Get(source.ArchiveURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %s", source.ArchiveURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch %s: unexpected status %s", source.ArchiveURL, response.Status)
+	}
+
+	archive, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", source.ArchiveURL, err)
+	}
+
+	if err := verifyChecksum(archive, checksum); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(cachePath, archive, 0644); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func verifyChecksum(archive []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	const prefix = "sha256:"
+	if !strings.HasPrefix(checksum, prefix) {
+		return fmt.Errorf("unsupported checksum format: %s (expected sha256:<hex>)", checksum)
+	}
+
+	expected := strings.TrimPrefix(checksum, prefix)
+	actual := sha256.Sum256(archive)
+	if hex.EncodeToString(actual[:]) != expected {
+		return fmt.Errorf("checksum mismatch: expected sha256:%s, got sha256:%s", expected, hex.EncodeToString(actual[:]))
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package remotesource lets `tenderly push` read contracts straight from a
+// tagged release archive instead of a local build directory, so a CI
+// runner can push exactly what was published at a tag without ever
+// running `truffle compile`/`hardhat compile` itself.
+package remotesource
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Source is a parsed --source value, resolved down to the HTTPS archive
+// URL it should be fetched from.
+type Source struct {
+	// Raw is the original --source value, as given. The fetch cache is
+	// keyed by ArchiveURL, not by Raw.
+	Raw string
+	// ArchiveURL is the HTTPS location of the tarball to download.
+	ArchiveURL string
+	// SubPath restricts artifact discovery to this directory inside the
+	// archive, when the `:path` suffix was given.
+	SubPath string
+}
+
+var (
+	githubSourcePattern = regexp.MustCompile(`^github://([^/]+)/([^@]+)@([^:]+)(?::(.+))?$`)
+	gitHTTPSSourcePattern = regexp.MustCompile(`^git\+(https://[^@]+)@([^:]+)(?::(.+))?$`)
+)
+
+// Parse interprets a --source value of the form
+// `github://owner/repo@tag[:path]` or `git+https://host/owner/repo@ref[:path]`.
+// A git+https value is only accepted when its host is github.com; any other
+// host is rejected with an error.
+func Parse(uri string) (*Source, error) {
+	if match := githubSourcePattern.FindStringSubmatch(uri); match != nil {
+		owner, repo, ref, subPath := match[1], match[2], match[3], match[4]
+		return &Source{
+			Raw:        uri,
+			ArchiveURL: fmt.Sprintf("https://codeload.github.com/%s/%s/tar.gz/%s", owner, repo, ref),
+			SubPath:    subPath,
+		}, nil
+	}
+
+	if match := gitHTTPSSourcePattern.FindStringSubmatch(uri); match != nil {
+		repoURL, ref, subPath := match[1], match[2], match[3]
+		if !strings.Contains(repoURL, "github.com") {
+			return nil, fmt.Errorf("git+https sources are currently only supported for github.com repositories, got: %s", repoURL)
+		}
+
+		trimmed := strings.TrimSuffix(strings.TrimPrefix(repoURL, "https://github.com/"), ".git")
+		parts := strings.SplitN(trimmed, "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unable to parse owner/repo out of: %s", repoURL)
+		}
+
+		return &Source{
+			Raw:        uri,
+			ArchiveURL: fmt.Sprintf("https://codeload.github.com/%s/%s/tar.gz/%s", parts[0], parts[1], ref),
+			SubPath:    subPath,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unrecognized --source value: %s (expected github://owner/repo@tag[:path] or git+https://host/owner/repo@ref[:path])", uri)
+}
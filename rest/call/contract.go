@@ -8,11 +8,19 @@ import (
 	"github.com/tenderly/tenderly-cli/config"
 	"github.com/tenderly/tenderly-cli/model"
 	"github.com/tenderly/tenderly-cli/rest/client"
+	"github.com/tenderly/tenderly-cli/rest/payloads"
 	"github.com/tenderly/tenderly-cli/truffle"
 )
 
 type UploadContractsRequest struct {
 	Contracts []truffle.Contract `json:"contracts"`
+	Config    *payloads.Config   `json:"config,omitempty"`
+	Tag       string             `json:"tag,omitempty"`
+}
+
+type UploadContractsResponse struct {
+	Contracts []*model.Contract  `json:"contracts"`
+	Error     *payloads.ApiError `json:"error,omitempty"`
 }
 
 type ContractCalls struct {
@@ -22,22 +30,32 @@ func NewContractCalls() *ContractCalls {
 	return &ContractCalls{}
 }
 
-func (rest *ContractCalls) UploadContracts(request UploadContractsRequest) ([]*model.Contract, error) {
+func (rest *ContractCalls) UploadContracts(request UploadContractsRequest, projectSlug string) (*UploadContractsResponse, error) {
 	contractsJson, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
 	}
 
-	var contracts []*model.Contract
-
 	response := client.Request(
 		"POST",
-		"api/v1/account/"+config.GetRCString("organisation")+"/project/"+config.GetRCString(config.ProjectSlug)+"/contracts",
+		"api/v1/account/"+config.GetRCString("organisation")+"/project/"+projectSlug+"/contracts",
 		viper.GetString("token"),
 		bytes.NewBuffer(contractsJson))
 
-	err = json.NewDecoder(response).Decode(&contracts)
-	return contracts, err
+	var uploadContractsResponse UploadContractsResponse
+	err = json.NewDecoder(response).Decode(&uploadContractsResponse)
+	return &uploadContractsResponse, err
+}
+
+// UploadContract uploads a single contract as a batch of one, so a single
+// failed (contract, network, address) tuple can be retried without
+// re-sending everything else in the project.
+func (rest *ContractCalls) UploadContract(contract truffle.Contract, config *payloads.Config, tag string, projectSlug string) (*UploadContractsResponse, error) {
+	return rest.UploadContracts(UploadContractsRequest{
+		Contracts: []truffle.Contract{contract},
+		Config:    config,
+		Tag:       tag,
+	}, projectSlug)
 }
 
 func (rest *ContractCalls) GetContracts(id string) ([]*model.Contract, error) {
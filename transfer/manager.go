@@ -0,0 +1,208 @@
+// Package transfer provides a small, reusable concurrent transfer manager
+// for scheduling a set of keyed operations (uploads, downloads, ...) across
+// a bounded worker pool with retries, backoff and progress reporting. It's
+// intentionally generic so it can back contract uploads today and other
+// bulk transfers later, similar in spirit to Docker's upload/download
+// manager.
+package transfer
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Descriptor is a single unit of work the TransferManager can schedule.
+// Key identifies the unit for deduplication purposes: if two Descriptors
+// queued on the same TransferManager share a Key, even across separate Run
+// calls, only the first is executed; the second is never run and is
+// reported as Skipped, not Done, since it did not actually perform the
+// transfer.
+type Descriptor interface {
+	Key() string
+	Do(ctx context.Context) error
+}
+
+// Config controls how a TransferManager retries and parallelizes work.
+type Config struct {
+	// Concurrency is the number of Descriptors processed at once. Defaults
+	// to 4 when left at zero.
+	Concurrency int
+	// MaxAttempts is the maximum number of attempts per Descriptor,
+	// including the first. Defaults to 3 when left at zero.
+	MaxAttempts int
+	// BaseBackoff is the starting delay for exponential backoff between
+	// retries. Defaults to 500ms when left at zero.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the computed backoff delay. Defaults to 10s when left
+	// at zero.
+	MaxBackoff time.Duration
+	// Watcher, if set, is notified of every status change.
+	Watcher Watcher
+	// IsRetryable decides whether an error returned by Do should be
+	// retried. Defaults to always retrying when left nil.
+	IsRetryable func(err error) bool
+}
+
+// TransferManager schedules Descriptors onto a bounded worker pool,
+// retrying failed attempts with exponential backoff and jitter, and
+// reporting progress through a Watcher.
+type TransferManager struct {
+	config Config
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewManager creates a TransferManager from the given Config, filling in
+// sane defaults for any zero-valued fields.
+func NewManager(config Config) *TransferManager {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = 500 * time.Millisecond
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 10 * time.Second
+	}
+	if config.IsRetryable == nil {
+		config.IsRetryable = func(err error) bool { return true }
+	}
+
+	return &TransferManager{
+		config: config,
+		seen:   make(map[string]struct{}),
+	}
+}
+
+// Result is the outcome of running a single Descriptor.
+type Result struct {
+	Key string
+	Err error
+	// Skipped is true when this Descriptor's Key had already been seen by
+	// this TransferManager, in this Run or an earlier one, so Do was never
+	// called for it.
+	Skipped bool
+}
+
+// Run executes every Descriptor across the configured worker pool and
+// blocks until all of them have finished, been deduplicated away, or ctx
+// was cancelled. It returns one Result per Descriptor; Descriptors sharing
+// a Key with one already scheduled by this TransferManager are never
+// executed and are reported back with Skipped set instead of Done.
+func (m *TransferManager) Run(ctx context.Context, descriptors []Descriptor) []Result {
+	jobs := make(chan Descriptor)
+	results := make([]Result, 0, len(descriptors))
+	resultsCh := make(chan Result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < m.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for descriptor := range jobs {
+				resultsCh <- Result{Key: descriptor.Key(), Err: m.run(ctx, descriptor)}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, descriptor := range descriptors {
+			if m.markSeen(descriptor.Key()) {
+				m.notify(Update{Key: descriptor.Key(), Status: StatusSkipped})
+				resultsCh <- Result{Key: descriptor.Key(), Skipped: true}
+				continue
+			}
+
+			select {
+			case jobs <- descriptor:
+			case <-ctx.Done():
+				resultsCh <- Result{Key: descriptor.Key(), Err: ctx.Err()}
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	for result := range resultsCh {
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// markSeen records key as scheduled for this manager's lifetime and reports
+// whether it had already been seen before.
+func (m *TransferManager) markSeen(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.seen[key]; ok {
+		return true
+	}
+	m.seen[key] = struct{}{}
+	return false
+}
+
+func (m *TransferManager) run(ctx context.Context, descriptor Descriptor) error {
+	var lastErr error
+
+	for attempt := 1; attempt <= m.config.MaxAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if attempt == 1 {
+			m.notify(Update{Key: descriptor.Key(), Status: StatusStarted, Attempt: attempt})
+		} else {
+			m.notify(Update{Key: descriptor.Key(), Status: StatusRetrying, Attempt: attempt, Err: lastErr})
+		}
+
+		err := descriptor.Do(ctx)
+		if err == nil {
+			m.notify(Update{Key: descriptor.Key(), Status: StatusDone, Attempt: attempt})
+			return nil
+		}
+
+		lastErr = err
+
+		if !m.config.IsRetryable(err) || attempt == m.config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(m.backoff(attempt)):
+		case <-ctx.Done():
+			lastErr = ctx.Err()
+		}
+	}
+
+	m.notify(Update{Key: descriptor.Key(), Status: StatusFailed, Attempt: m.config.MaxAttempts, Err: lastErr})
+	return lastErr
+}
+
+// backoff computes an exponential delay for the given attempt with full
+// jitter, capped at MaxBackoff.
+func (m *TransferManager) backoff(attempt int) time.Duration {
+	delay := m.config.BaseBackoff * time.Duration(1<<uint(attempt-1))
+	if delay > m.config.MaxBackoff {
+		delay = m.config.MaxBackoff
+	}
+
+	return time.Duration(rand.Int63n(int64(delay)))
+}
+
+func (m *TransferManager) notify(update Update) {
+	if m.config.Watcher != nil {
+		m.config.Watcher.OnUpdate(update)
+	}
+}
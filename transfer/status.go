@@ -0,0 +1,60 @@
+package transfer
+
+// Status describes the current lifecycle state of a single Descriptor as it
+// moves through the TransferManager.
+type Status int
+
+const (
+	StatusQueued Status = iota
+	StatusStarted
+	StatusRetrying
+	StatusDone
+	StatusFailed
+	// StatusSkipped is reported for a Descriptor whose Key was already seen
+	// by the same TransferManager: its Do was never called, so it must not be
+	// confused with a Descriptor that actually ran and succeeded.
+	StatusSkipped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusQueued:
+		return "Queued"
+	case StatusStarted:
+		return "Started"
+	case StatusRetrying:
+		return "Retrying"
+	case StatusDone:
+		return "Done"
+	case StatusFailed:
+		return "Failed"
+	case StatusSkipped:
+		return "Skipped"
+	default:
+		return "Unknown"
+	}
+}
+
+// Update is a single status change reported for a Descriptor, handed to the
+// registered Watcher so callers can render progress without the manager
+// knowing anything about how it's displayed.
+type Update struct {
+	Key     string
+	Status  Status
+	Attempt int
+	Err     error
+}
+
+// Watcher receives Updates as Descriptors progress through the manager. It
+// is called from whichever worker goroutine produced the Update, so
+// implementations must be safe for concurrent use.
+type Watcher interface {
+	OnUpdate(update Update)
+}
+
+// WatcherFunc adapts a plain function to the Watcher interface.
+type WatcherFunc func(update Update)
+
+func (f WatcherFunc) OnUpdate(update Update) {
+	f(update)
+}